@@ -1,14 +1,18 @@
+// Command debug-go-client sends a single hand-built "manifest" request over
+// a Unix domain datagram socket, for manually exercising a running
+// JanusServer's unixgram transport without pkg/protocol.
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net"
-	"os"
 	"time"
+
+	"GoJanus/pkg/transport"
 )
 
-type JanusRequest struct {
+type janusRequest struct {
 	ID        string                 `json:"id"`
 	Method    string                 `json:"method"`
 	ChannelID string                 `json:"channelId"`
@@ -22,8 +26,7 @@ type JanusRequest struct {
 func main() {
 	fmt.Println("Starting debug Go client...")
 
-	// Create a manifest request
-	request := JanusRequest{
+	request := janusRequest{
 		ID:        "debug-test-123",
 		Method:    "manifest",
 		ChannelID: "system",
@@ -34,7 +37,6 @@ func main() {
 		Timestamp: time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
 	}
 
-	// Marshal to JSON
 	requestData, err := json.Marshal(request)
 	if err != nil {
 		fmt.Printf("Error marshaling request: %v\n", err)
@@ -43,38 +45,19 @@ func main() {
 
 	fmt.Printf("Sending request: %s\n", string(requestData))
 
-	// Create Unix datagram socket with temporary client socket
-	clientAddr, err := net.ResolveUnixAddr("unixgram", "/tmp/go_debug_client.sock")
-	if err != nil {
-		fmt.Printf("Error resolving client address: %v\n", err)
-		return
-	}
-
-	// Clean up any existing client socket
-	os.Remove("/tmp/go_debug_client.sock")
-
-	conn, err := net.ListenUnixgram("unixgram", clientAddr)
+	conn, err := transport.UnixDatagram{SocketPath: "/tmp/rust_janus_test.sock"}.Dial(context.Background())
 	if err != nil {
-		fmt.Printf("Error creating client socket: %v\n", err)
+		fmt.Printf("Error dialing server: %v\n", err)
 		return
 	}
 	defer conn.Close()
-	defer os.Remove("/tmp/go_debug_client.sock")
 
-	// Resolve server address
-	serverAddr, err := net.ResolveUnixAddr("unixgram", "/tmp/rust_janus_test.sock")
-	if err != nil {
-		fmt.Printf("Error resolving server address: %v\n", err)
-		return
-	}
-
-	// Send the request using WriteTo
-	n, err := conn.WriteTo(requestData, serverAddr)
+	n, err := conn.Write(requestData)
 	if err != nil {
 		fmt.Printf("Error sending request: %v\n", err)
 		return
 	}
 
-	fmt.Printf("Successfully sent %d bytes to Rust server\n", n)
+	fmt.Printf("Successfully sent %d bytes to server\n", n)
 	fmt.Println("Request sent successfully!")
-}
\ No newline at end of file
+}
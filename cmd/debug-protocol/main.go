@@ -0,0 +1,55 @@
+// Command debug-protocol sends a single hand-built "ping" request over a
+// Unix domain stream socket and prints the raw response, for manually
+// exercising a running JanusServer's wire framing without pkg/protocol.
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"GoJanus/pkg/transport"
+)
+
+func main() {
+	conn, err := transport.Unix{SocketPath: "/tmp/test_manual.sock"}.Dial(context.Background())
+	if err != nil {
+		panic(err)
+	}
+	defer conn.Close()
+
+	command := map[string]interface{}{
+		"id":        "test-123",
+		"channelId": "test",
+		"command":   "ping",
+		"args":      map[string]interface{}{},
+		"timeout":   5.0,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	}
+
+	cmdBytes, _ := json.Marshal(command)
+	fmt.Printf("Sending command: %s\n", string(cmdBytes))
+
+	// Send with 4-byte length prefix, matching transport.FramingStream.
+	if err := binary.Write(conn, binary.BigEndian, uint32(len(cmdBytes))); err != nil {
+		panic(err)
+	}
+	if _, err := conn.Write(cmdBytes); err != nil {
+		panic(err)
+	}
+
+	var respLength uint32
+	if err := binary.Read(conn, binary.BigEndian, &respLength); err != nil {
+		panic(err)
+	}
+	fmt.Printf("Response length: %d\n", respLength)
+
+	respBytes := make([]byte, respLength)
+	if _, err := io.ReadFull(conn, respBytes); err != nil {
+		panic(err)
+	}
+	fmt.Printf("Response: %s\n", string(respBytes))
+}
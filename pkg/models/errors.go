@@ -0,0 +1,16 @@
+package models
+
+// Well-known error codes returned in JanusError.Code.
+const (
+	ErrCodeInternal         = "INTERNAL_ERROR"
+	ErrCodeUnknownRequest   = "UNKNOWN_REQUEST"
+	ErrCodeInvalidArguments = "INVALID_ARGUMENTS"
+	ErrCodeTimeout          = "TIMEOUT"
+	// ErrCodeServerShuttingDown is returned for requests that arrive (or
+	// resume dispatch) once a server has entered its lame-duck drain
+	// period; clients should treat it as retryable against another server.
+	ErrCodeServerShuttingDown = "SERVER_SHUTTING_DOWN"
+	// ErrCodePermissionDenied is returned when a ServerConfig.Authorizer
+	// rejects a request's peer credentials.
+	ErrCodePermissionDenied = "PERMISSION_DENIED"
+)
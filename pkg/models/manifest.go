@@ -0,0 +1,10 @@
+package models
+
+// Manifest describes a running server: its version, the request handlers
+// ("models") it has registered, and the transports it can be reached
+// over, as returned by the built-in "manifest" request.
+type Manifest struct {
+	Version    string                 `json:"version"`
+	Models     map[string]interface{} `json:"models"`
+	Transports []string               `json:"transports"`
+}
@@ -0,0 +1,26 @@
+// Package models defines the wire-level request/response types shared by
+// the Janus client and server implementations.
+package models
+
+import "time"
+
+// JanusRequest is the native Janus envelope sent from client to server.
+type JanusRequest struct {
+	ID        string                 `json:"id"`
+	ChannelID string                 `json:"channelId,omitempty"`
+	Request   string                 `json:"request"`
+	ReplyTo   *string                `json:"reply_to,omitempty"`
+	Args      map[string]interface{} `json:"args,omitempty"`
+	Timeout   float64                `json:"timeout,omitempty"`
+	Timestamp string                 `json:"timestamp"`
+}
+
+// NewRequest builds a JanusRequest stamped with the current time.
+func NewRequest(id, request string, args map[string]interface{}) *JanusRequest {
+	return &JanusRequest{
+		ID:        id,
+		Request:   request,
+		Args:      args,
+		Timestamp: time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+}
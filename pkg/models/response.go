@@ -0,0 +1,31 @@
+package models
+
+// JanusResponse is the native Janus envelope sent from server to client.
+//
+// Seq and Done are only populated for streamed responses delivered to a
+// request's ReplyTo address (see server.Stream and Client.Subscribe): Seq
+// is the 0-based position of this message within the stream, and Done
+// marks the final message.
+type JanusResponse struct {
+	RequestID string      `json:"request_id"`
+	ID        string      `json:"id"`
+	Success   bool        `json:"success"`
+	Result    interface{} `json:"result,omitempty"`
+	Error     *JanusError `json:"error,omitempty"`
+	Seq       *int        `json:"seq,omitempty"`
+	Done      bool        `json:"done,omitempty"`
+	Timestamp string      `json:"timestamp"`
+}
+
+// JanusError is the structured error carried by a failed JanusResponse.
+type JanusError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *JanusError) Error() string {
+	if e == nil {
+		return ""
+	}
+	return e.Code + ": " + e.Message
+}
@@ -0,0 +1,231 @@
+// Package protocol implements the Janus client: it opens a connection
+// over a pluggable transport.Transport, frames a request, and waits for
+// the matching response.
+package protocol
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"GoJanus/pkg/models"
+	"GoJanus/pkg/transport"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// maxPacketMessageSize bounds a single read on a packet-framed transport
+// (see transport.FramingPacket), mirroring the datagram size transport's
+// own listener allows.
+const maxPacketMessageSize = 64 * 1024
+
+// RetryPolicy controls how a Client reacts to a SERVER_SHUTTING_DOWN
+// response (or a transport error that looks like one): it backs off and
+// redials up to MaxRetries times instead of surfacing the failure.
+type RetryPolicy struct {
+	MaxRetries int
+	Backoff    time.Duration
+}
+
+// Option configures a Client at construction time.
+type Option func(*Client)
+
+// WithRetryPolicy makes the Client retry requests that fail because the
+// server is draining, rather than returning the error immediately.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) { c.retry = &policy }
+}
+
+// WithCodec selects the wire format a Client constructed via
+// NewWithTransport uses; New and NewWithCodec set this directly.
+func WithCodec(codec CodecType) Option {
+	return func(c *Client) { c.codecType = codec }
+}
+
+// Client sends Janus requests over a transport.Transport and decodes the
+// response using its configured Codec.
+type Client struct {
+	transport transport.Transport
+	codecType CodecType
+	codec     Codec
+	nextID    uint64
+	retry     *RetryPolicy
+}
+
+// New creates a Client that dials socketPath over a Unix domain stream
+// socket using the native Janus wire format.
+func New(socketPath string, opts ...Option) (*Client, error) {
+	return NewWithTransport(transport.Unix{SocketPath: socketPath}, opts...)
+}
+
+// NewWithCodec creates a Client that dials socketPath over a Unix domain
+// stream socket using the given wire format.
+func NewWithCodec(socketPath string, codec CodecType, opts ...Option) (*Client, error) {
+	return NewWithTransport(transport.Unix{SocketPath: socketPath}, append([]Option{WithCodec(codec)}, opts...)...)
+}
+
+// NewWithTransport creates a Client that communicates over an arbitrary
+// transport.Transport (Unix, TCP/TLS, vsock, ...), using the native
+// Janus wire format unless overridden with WithCodec.
+func NewWithTransport(t transport.Transport, opts ...Option) (*Client, error) {
+	client := &Client{transport: t, codecType: CodecNative}
+	for _, opt := range opts {
+		opt(client)
+	}
+	codec, err := codecFor(client.codecType)
+	if err != nil {
+		return nil, err
+	}
+	client.codec = codec
+	return client, nil
+}
+
+func (c *Client) newRequestID() string {
+	id := atomic.AddUint64(&c.nextID, 1)
+	return fmt.Sprintf("%d", id)
+}
+
+// SendRequest opens a connection, sends request/args, and returns the
+// decoded response. The connection is closed once the response has been
+// read; Janus does not keep a persistent connection between requests.
+// If ctx carries a deadline, it bounds both the dial and the round trip;
+// otherwise defaultTimeout applies.
+//
+// If the Client was built with WithRetryPolicy and the server reports
+// SERVER_SHUTTING_DOWN (or drops the connection the way a shutting-down
+// server does), SendRequest backs off and redials instead of surfacing
+// the failure, up to RetryPolicy.MaxRetries times.
+func (c *Client) SendRequest(ctx context.Context, request string, args map[string]interface{}) (*models.JanusResponse, error) {
+	attempts := 1
+	if c.retry != nil {
+		attempts += c.retry.MaxRetries
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(c.retry.Backoff):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		resp, err := c.sendOnce(ctx, request, args)
+		if err == nil && !isShuttingDown(resp) {
+			return resp, nil
+		}
+		if err == nil {
+			lastErr = resp.Error
+		} else {
+			lastErr = err
+		}
+		if c.retry == nil || !isRetryable(resp, err) {
+			return resp, err
+		}
+	}
+	return nil, lastErr
+}
+
+func isShuttingDown(resp *models.JanusResponse) bool {
+	return resp != nil && resp.Error != nil && resp.Error.Code == models.ErrCodeServerShuttingDown
+}
+
+// isRetryable reports whether a failed attempt looks like a draining
+// server rather than a permanent error: either the server told us so
+// explicitly, or the connection was dropped the way a closed listener
+// drops one (EOF/connection refused) while draining.
+func isRetryable(resp *models.JanusResponse, err error) bool {
+	if isShuttingDown(resp) {
+		return true
+	}
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+func (c *Client) sendOnce(ctx context.Context, request string, args map[string]interface{}) (*models.JanusResponse, error) {
+	req := models.NewRequest(c.newRequestID(), request, args)
+
+	deadline := defaultTimeout
+	if dl, ok := ctx.Deadline(); ok {
+		deadline = time.Until(dl)
+	} else {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, deadline)
+		defer cancel()
+	}
+	req.Timeout = deadline.Seconds()
+
+	conn, err := c.transport.Dial(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("protocol: dial: %w", err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(deadline))
+
+	payload, err := c.codec.EncodeRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("protocol: encode request: %w", err)
+	}
+	if err := writeMessage(conn, c.transport.Framing(), payload); err != nil {
+		return nil, fmt.Errorf("protocol: write request: %w", err)
+	}
+
+	respBytes, err := readMessage(conn, c.transport.Framing())
+	if err != nil {
+		return nil, fmt.Errorf("protocol: read response: %w", err)
+	}
+
+	return c.codec.DecodeResponse(respBytes, req)
+}
+
+func writeMessage(w io.Writer, framing transport.Framing, payload []byte) error {
+	if framing == transport.FramingPacket {
+		_, err := w.Write(payload)
+		return err
+	}
+	return writeFrame(w, payload)
+}
+
+func readMessage(r io.Reader, framing transport.Framing) ([]byte, error) {
+	if framing == transport.FramingPacket {
+		buf := make([]byte, maxPacketMessageSize)
+		n, err := r.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	}
+	return readFrame(r)
+}
+
+// writeFrame writes a 4-byte big-endian length prefix followed by payload.
+func writeFrame(w io.Writer, payload []byte) error {
+	if err := binary.Write(w, binary.BigEndian, uint32(len(payload))); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+// readFrame reads a 4-byte big-endian length prefix followed by that many
+// bytes of payload.
+func readFrame(r io.Reader) ([]byte, error) {
+	var length uint32
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
@@ -0,0 +1,47 @@
+package protocol
+
+import "GoJanus/pkg/models"
+
+// CodecType selects the wire format a Client uses to talk to a server.
+type CodecType int
+
+const (
+	// CodecNative is the bespoke Janus JSON envelope (the historical default).
+	CodecNative CodecType = iota
+	// CodecJSONRPC2 serializes requests/responses as JSON-RPC 2.0 so Janus
+	// can interoperate with other JSON-RPC tooling.
+	CodecJSONRPC2
+)
+
+// Codec encodes outgoing requests and decodes incoming responses for a
+// single wire format. Both directions share one Codec so a Client and a
+// server dispatcher stay in sync about framing.
+type Codec interface {
+	// EncodeRequest serializes req into the bytes placed on the wire,
+	// without the length prefix (framing is handled by the transport).
+	EncodeRequest(req *models.JanusRequest) ([]byte, error)
+	// DecodeResponse parses bytes read off the wire into a JanusResponse.
+	// req is the original request, used to recover fields (such as the
+	// request id) that a given wire format may not echo back verbatim.
+	DecodeResponse(data []byte, req *models.JanusRequest) (*models.JanusResponse, error)
+}
+
+func codecFor(t CodecType) (Codec, error) {
+	switch t {
+	case CodecNative:
+		return nativeCodec{}, nil
+	case CodecJSONRPC2:
+		return jsonRPC2Codec{}, nil
+	default:
+		return nil, &UnsupportedCodecError{Codec: t}
+	}
+}
+
+// UnsupportedCodecError is returned by NewWithCodec for an unknown CodecType.
+type UnsupportedCodecError struct {
+	Codec CodecType
+}
+
+func (e *UnsupportedCodecError) Error() string {
+	return "protocol: unsupported codec type"
+}
@@ -0,0 +1,87 @@
+package protocol
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"GoJanus/pkg/models"
+)
+
+// jsonRPC2Request is the wire shape of a JSON-RPC 2.0 request object. ID is
+// kept as raw JSON, since Janus's own ids are strings but the spec (and
+// most JSON-RPC servers) also allow numeric ids.
+type jsonRPC2Request struct {
+	JSONRPC string                 `json:"jsonrpc"`
+	ID      json.RawMessage        `json:"id"`
+	Method  string                 `json:"method"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+}
+
+// jsonRPC2Response is the wire shape of a JSON-RPC 2.0 response object.
+type jsonRPC2Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *jsonRPC2Error  `json:"error,omitempty"`
+}
+
+type jsonRPC2Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// jsonRPC2Codec serializes Janus requests/responses as JSON-RPC 2.0 so a
+// Janus server can be driven by any JSON-RPC 2.0 capable client, and a
+// Janus client can talk to any JSON-RPC 2.0 server that understands the
+// same method names.
+type jsonRPC2Codec struct{}
+
+func (jsonRPC2Codec) EncodeRequest(req *models.JanusRequest) ([]byte, error) {
+	id, err := json.Marshal(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonRPC2Request{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  req.Request,
+		Params:  req.Args,
+	})
+}
+
+func (jsonRPC2Codec) DecodeResponse(data []byte, req *models.JanusRequest) (*models.JanusResponse, error) {
+	var rpc jsonRPC2Response
+	if err := json.Unmarshal(data, &rpc); err != nil {
+		return nil, err
+	}
+
+	resp := &models.JanusResponse{
+		RequestID: req.ID,
+		ID:        jsonRPC2IDToString(rpc.ID),
+		Success:   rpc.Error == nil,
+		Result:    rpc.Result,
+	}
+	if rpc.Error != nil {
+		resp.Error = &models.JanusError{
+			Code:    strconv.Itoa(rpc.Error.Code),
+			Message: rpc.Error.Message,
+		}
+	}
+	return resp, nil
+}
+
+// jsonRPC2IDToString derives the string form of a JanusResponse id from a
+// raw JSON-RPC id, which per spec may be a string or a number: a numeric id
+// is rendered as its decimal digits, a string id is unquoted, and a
+// missing/null id becomes "".
+func jsonRPC2IDToString(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var n json.Number
+	if json.Unmarshal(raw, &n) == nil {
+		return n.String()
+	}
+	return ""
+}
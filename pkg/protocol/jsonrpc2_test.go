@@ -0,0 +1,169 @@
+package protocol_test
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"GoJanus/pkg/protocol"
+	"GoJanus/pkg/server"
+)
+
+// TestJSONRPC2Interop verifies that a client created with CodecJSONRPC2 can
+// complete a full request/response round trip against the native
+// JanusServer dispatcher, which auto-detects the wire format per request.
+func TestJSONRPC2Interop(t *testing.T) {
+	socketPath := fmt.Sprintf("/tmp/go-jsonrpc2-test-%d.sock", time.Now().UnixNano())
+	defer os.Remove(socketPath)
+
+	srv := server.NewJanusServer(&server.ServerConfig{SocketPath: socketPath})
+
+	ready := make(chan bool, 1)
+	srv.On("listening", func(data interface{}) {
+		select {
+		case ready <- true:
+		default:
+		}
+	})
+	go srv.StartListening()
+
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server startup timeout")
+	}
+
+	client, err := protocol.NewWithCodec(socketPath, protocol.CodecJSONRPC2)
+	if err != nil {
+		t.Fatalf("NewWithCodec: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.SendRequest(ctx, "ping", nil)
+	if err != nil {
+		t.Fatalf("SendRequest: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %v", resp.Error)
+	}
+}
+
+// TestJSONRPC2UnknownRequest verifies that an unknown method still produces
+// a well-formed JSON-RPC 2.0 error response rather than a protocol error.
+func TestJSONRPC2UnknownRequest(t *testing.T) {
+	socketPath := fmt.Sprintf("/tmp/go-jsonrpc2-unknown-test-%d.sock", time.Now().UnixNano())
+	defer os.Remove(socketPath)
+
+	srv := server.NewJanusServer(&server.ServerConfig{SocketPath: socketPath})
+
+	ready := make(chan bool, 1)
+	srv.On("listening", func(data interface{}) {
+		select {
+		case ready <- true:
+		default:
+		}
+	})
+	go srv.StartListening()
+
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server startup timeout")
+	}
+
+	client, err := protocol.NewWithCodec(socketPath, protocol.CodecJSONRPC2)
+	if err != nil {
+		t.Fatalf("NewWithCodec: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.SendRequest(ctx, "no_such_request", nil)
+	if err != nil {
+		t.Fatalf("SendRequest: %v", err)
+	}
+	if resp.Success {
+		t.Fatal("expected failure for unknown request")
+	}
+	if resp.Error == nil || resp.Error.Code != "-32601" {
+		t.Fatalf("expected the standard JSON-RPC 2.0 \"method not found\" code -32601, got %+v", resp.Error)
+	}
+}
+
+// TestJSONRPC2NumericID verifies the server accepts the canonical
+// numeric-id wire form (as opposed to Janus's own string ids) and echoes
+// it back unchanged, per the JSON-RPC 2.0 spec.
+func TestJSONRPC2NumericID(t *testing.T) {
+	socketPath := fmt.Sprintf("/tmp/go-jsonrpc2-numeric-id-test-%d.sock", time.Now().UnixNano())
+	defer os.Remove(socketPath)
+
+	srv := server.NewJanusServer(&server.ServerConfig{SocketPath: socketPath})
+
+	ready := make(chan bool, 1)
+	srv.On("listening", func(data interface{}) {
+		select {
+		case ready <- true:
+		default:
+		}
+	})
+	go srv.StartListening()
+
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server startup timeout")
+	}
+
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+
+	if err := writeFrame(conn, []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`)); err != nil {
+		t.Fatalf("writeFrame: %v", err)
+	}
+
+	payload, err := readFrame(conn)
+	if err != nil {
+		t.Fatalf("readFrame: %v", err)
+	}
+
+	var rpc struct {
+		ID     json.RawMessage `json:"id"`
+		Result interface{}     `json:"result"`
+	}
+	if err := json.Unmarshal(payload, &rpc); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if string(rpc.ID) != "1" {
+		t.Fatalf("expected the numeric id 1 echoed back verbatim, got %q", rpc.ID)
+	}
+}
+
+func writeFrame(w net.Conn, payload []byte) error {
+	if err := binary.Write(w, binary.BigEndian, uint32(len(payload))); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+func readFrame(r net.Conn) ([]byte, error) {
+	var length uint32
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, length)
+	_, err := io.ReadFull(r, buf)
+	return buf, err
+}
@@ -0,0 +1,23 @@
+package protocol
+
+import (
+	"encoding/json"
+
+	"GoJanus/pkg/models"
+)
+
+// nativeCodec is the original bespoke Janus JSON envelope:
+// {"id":..., "request":..., "args":..., "reply_to":..., "timestamp":...}
+type nativeCodec struct{}
+
+func (nativeCodec) EncodeRequest(req *models.JanusRequest) ([]byte, error) {
+	return json.Marshal(req)
+}
+
+func (nativeCodec) DecodeResponse(data []byte, req *models.JanusRequest) (*models.JanusResponse, error) {
+	var resp models.JanusResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,70 @@
+package protocol_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"GoJanus/pkg/protocol"
+	"GoJanus/pkg/server"
+)
+
+// TestSendRequestRetriesAfterShutdown verifies that a Client configured
+// with a RetryPolicy redials and succeeds against a second server once
+// the first has drained, instead of surfacing the SERVER_SHUTTING_DOWN
+// response (or the connection failure from the first server's closed
+// socket) as a hard error.
+func TestSendRequestRetriesAfterShutdown(t *testing.T) {
+	socketPath := fmt.Sprintf("/tmp/go-retry-test-%d.sock", time.Now().UnixNano())
+	defer os.Remove(socketPath)
+
+	srv := server.NewJanusServer(&server.ServerConfig{SocketPath: socketPath})
+
+	ready := make(chan bool, 1)
+	srv.On("listening", func(data interface{}) {
+		select {
+		case ready <- true:
+		default:
+		}
+	})
+	go srv.StartListening()
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server startup timeout")
+	}
+
+	client, err := protocol.New(socketPath, protocol.WithRetryPolicy(protocol.RetryPolicy{
+		MaxRetries: 3,
+		Backoff:    10 * time.Millisecond,
+	}))
+	if err != nil {
+		t.Fatalf("protocol.New: %v", err)
+	}
+
+	// Shut the server down in the background while a retrying client is
+	// mid-flight; a fresh server takes over the same socket path shortly
+	// after, simulating a caller that retries "elsewhere".
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		srv.Shutdown(context.Background())
+		time.Sleep(20 * time.Millisecond)
+
+		srv2 := server.NewJanusServer(&server.ServerConfig{SocketPath: socketPath})
+		srv2.On("listening", func(data interface{}) {})
+		srv2.StartListening()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.SendRequest(ctx, "ping", nil)
+	if err != nil {
+		t.Fatalf("SendRequest: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success after retry, got %+v", resp)
+	}
+}
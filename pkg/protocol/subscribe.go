@@ -0,0 +1,100 @@
+package protocol
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+
+	"GoJanus/pkg/models"
+)
+
+// CancelFunc stops a subscription started by Subscribe: it tears down the
+// subscriber's reply_to socket, which unblocks the delivery goroutine and
+// closes the channel Subscribe returned.
+type CancelFunc func()
+
+// Subscribe sends request/args like SendRequest, but binds a dedicated
+// reply_to socket first and returns a channel fed by every response the
+// server streams to it (see server.Stream), instead of waiting for a
+// single reply. The channel is closed once a streamed response carries
+// Done, ctx is done, or the returned CancelFunc is called.
+//
+// Subscribe only makes sense against a handler that returns a
+// server.Stream; against an ordinary handler it will just wait until ctx
+// is done, since no reply_to response ever arrives.
+func (c *Client) Subscribe(ctx context.Context, request string, args map[string]interface{}) (<-chan models.JanusResponse, CancelFunc, error) {
+	replyPath := fmt.Sprintf("/tmp/janus-sub-%d-%d.sock", os.Getpid(), atomic.AddUint64(&c.nextID, 1))
+	replyConn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: replyPath, Net: "unixgram"})
+	if err != nil {
+		return nil, nil, fmt.Errorf("protocol: subscribe: bind reply_to: %w", err)
+	}
+
+	req := models.NewRequest(c.newRequestID(), request, args)
+	req.ReplyTo = &replyPath
+
+	conn, err := c.transport.Dial(ctx)
+	if err != nil {
+		replyConn.Close()
+		os.Remove(replyPath)
+		return nil, nil, fmt.Errorf("protocol: subscribe: dial: %w", err)
+	}
+
+	payload, err := c.codec.EncodeRequest(req)
+	if err == nil {
+		err = writeMessage(conn, c.transport.Framing(), payload)
+	}
+	conn.Close()
+	if err != nil {
+		replyConn.Close()
+		os.Remove(replyPath)
+		return nil, nil, fmt.Errorf("protocol: subscribe: send request: %w", err)
+	}
+
+	ch := make(chan models.JanusResponse)
+	cancelled := make(chan struct{})
+	var cancelOnce int32
+	cancel := CancelFunc(func() {
+		if atomic.CompareAndSwapInt32(&cancelOnce, 0, 1) {
+			close(cancelled)
+			replyConn.Close()
+		}
+	})
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			cancel()
+		case <-cancelled:
+		}
+	}()
+
+	go func() {
+		defer close(ch)
+		defer os.Remove(replyPath)
+		buf := make([]byte, maxPacketMessageSize)
+		for {
+			n, err := replyConn.Read(buf)
+			if err != nil {
+				return
+			}
+			var resp models.JanusResponse
+			if err := json.Unmarshal(buf[:n], &resp); err != nil {
+				continue
+			}
+			select {
+			case ch <- resp:
+			case <-cancelled:
+				return
+			}
+			if resp.Done {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	return ch, cancel, nil
+}
@@ -0,0 +1,114 @@
+package protocol_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"GoJanus/pkg/protocol"
+	"GoJanus/pkg/server"
+)
+
+// TestSubscribeReceivesStreamedValues verifies that a handler returning a
+// server.Stream delivers every emitted value to Client.Subscribe's
+// channel, with increasing seq numbers, terminated by a done:true message.
+func TestSubscribeReceivesStreamedValues(t *testing.T) {
+	socketPath := fmt.Sprintf("/tmp/go-subscribe-test-%d.sock", time.Now().UnixNano())
+	defer os.Remove(socketPath)
+
+	srv := server.NewJanusServer(&server.ServerConfig{SocketPath: socketPath})
+	srv.Handle("count_to_three", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		stream := server.NewStream()
+		go func() {
+			for i := 1; i <= 3; i++ {
+				stream.Send(map[string]interface{}{"n": i})
+			}
+			stream.Close()
+		}()
+		return stream, nil
+	})
+	startServerOn(t, srv)
+
+	client, err := protocol.New(socketPath)
+	if err != nil {
+		t.Fatalf("protocol.New: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ch, stop, err := client.Subscribe(ctx, "count_to_three", nil)
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	defer stop()
+
+	var gotSeq []int
+	sawDone := false
+	for resp := range ch {
+		if resp.Seq == nil {
+			t.Fatalf("expected seq on streamed response, got %+v", resp)
+		}
+		gotSeq = append(gotSeq, *resp.Seq)
+		if resp.Done {
+			sawDone = true
+		}
+	}
+
+	if !sawDone {
+		t.Fatal("expected a final done:true message")
+	}
+	if len(gotSeq) != 4 { // 3 values + 1 terminal
+		t.Fatalf("expected 4 messages (3 values + done), got %d: %+v", len(gotSeq), gotSeq)
+	}
+	for i, seq := range gotSeq {
+		if seq != i {
+			t.Fatalf("expected seq %d at position %d, got %+v", i, i, gotSeq)
+		}
+	}
+}
+
+// TestSubscribeWatchEvents verifies the built-in watch_events request
+// streams the server's own lifecycle events to a subscriber.
+func TestSubscribeWatchEvents(t *testing.T) {
+	socketPath := fmt.Sprintf("/tmp/go-watchevents-test-%d.sock", time.Now().UnixNano())
+	defer os.Remove(socketPath)
+
+	srv := server.NewJanusServer(&server.ServerConfig{SocketPath: socketPath})
+	startServerOn(t, srv)
+
+	client, err := protocol.New(socketPath)
+	if err != nil {
+		t.Fatalf("protocol.New: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ch, stop, err := client.Subscribe(ctx, "watch_events", nil)
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	defer stop()
+
+	// Give the server time to dispatch the subscription and register its
+	// event listeners before triggering the "draining" event it should
+	// forward.
+	time.Sleep(50 * time.Millisecond)
+	go srv.Shutdown(context.Background())
+
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			t.Fatal("channel closed before any event arrived")
+		}
+		result, ok := resp.Result.(map[string]interface{})
+		if !ok || result["event"] != "draining" {
+			t.Fatalf("expected a draining event, got %+v", resp.Result)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for draining event")
+	}
+}
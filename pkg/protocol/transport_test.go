@@ -0,0 +1,128 @@
+package protocol_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"GoJanus/pkg/protocol"
+	"GoJanus/pkg/server"
+	"GoJanus/pkg/transport"
+)
+
+// startServerOn starts srv in the background and blocks until its
+// "listening" event fires, or fails the test after a timeout.
+func startServerOn(t *testing.T, srv *server.JanusServer) {
+	t.Helper()
+	ready := make(chan bool, 1)
+	srv.On("listening", func(data interface{}) {
+		select {
+		case ready <- true:
+		default:
+		}
+	})
+	go srv.StartListening()
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server startup timeout")
+	}
+}
+
+// TestTCPTransportInterop verifies a client and server can exchange a
+// request/response over transport.TCP, exercising the stream-framed path
+// on a non-Unix-socket transport.
+func TestTCPTransportInterop(t *testing.T) {
+	addr := "127.0.0.1:0"
+	tr := transport.TCP{Addr: addr}
+	listener, err := tr.Listen()
+	if err != nil {
+		t.Fatalf("transport.TCP.Listen: %v", err)
+	}
+	listener.Close()
+
+	tr = transport.TCP{Addr: listener.Addr().String()}
+	srv := server.NewJanusServer(&server.ServerConfig{Transport: tr})
+	startServerOn(t, srv)
+
+	client, err := protocol.NewWithTransport(tr)
+	if err != nil {
+		t.Fatalf("protocol.NewWithTransport: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.SendRequest(ctx, "ping", nil)
+	if err != nil {
+		t.Fatalf("SendRequest: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got %+v", resp)
+	}
+}
+
+// TestUnixDatagramTransportInterop verifies a client and server can
+// exchange a request/response over transport.UnixDatagram, exercising the
+// packet-framed path (single datagram in, single datagram out).
+func TestUnixDatagramTransportInterop(t *testing.T) {
+	socketPath := fmt.Sprintf("/tmp/go-unixgram-test-%d.sock", time.Now().UnixNano())
+	defer os.Remove(socketPath)
+
+	tr := transport.UnixDatagram{SocketPath: socketPath}
+	srv := server.NewJanusServer(&server.ServerConfig{Transport: tr})
+	startServerOn(t, srv)
+
+	client, err := protocol.NewWithTransport(tr)
+	if err != nil {
+		t.Fatalf("protocol.NewWithTransport: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.SendRequest(ctx, "ping", nil)
+	if err != nil {
+		t.Fatalf("SendRequest: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got %+v", resp)
+	}
+}
+
+// TestManifestAdvertisesTransport verifies the built-in manifest request
+// reports which transport the server is listening on.
+func TestManifestAdvertisesTransport(t *testing.T) {
+	socketPath := fmt.Sprintf("/tmp/go-manifest-test-%d.sock", time.Now().UnixNano())
+	defer os.Remove(socketPath)
+
+	srv := server.NewJanusServer(&server.ServerConfig{SocketPath: socketPath})
+	startServerOn(t, srv)
+
+	client, err := protocol.New(socketPath)
+	if err != nil {
+		t.Fatalf("protocol.New: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.SendRequest(ctx, "manifest", nil)
+	if err != nil {
+		t.Fatalf("SendRequest: %v", err)
+	}
+	if !resp.Success {
+		t.Fatalf("expected success, got %+v", resp)
+	}
+
+	result, ok := resp.Result.(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected map result, got %T", resp.Result)
+	}
+	transports, ok := result["transports"].([]interface{})
+	if !ok || len(transports) != 1 || transports[0] != "unix" {
+		t.Fatalf("expected transports [\"unix\"], got %+v", result["transports"])
+	}
+}
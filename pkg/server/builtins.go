@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"GoJanus/pkg/models"
+)
+
+func (s *JanusServer) registerBuiltins() {
+	s.handlers["ping"] = handlePing
+	s.handlers["manifest"] = s.handleManifest
+	s.handlers["echo"] = handleEcho
+	s.handlers["validate"] = handleValidate
+	s.handlers["get_info"] = handleGetInfo
+	s.handlers["slow_process"] = handleSlowProcess
+	s.handlers["watch_events"] = s.handleWatchEvents
+}
+
+func handlePing(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	return map[string]interface{}{"pong": true}, nil
+}
+
+func (s *JanusServer) handleManifest(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	return models.Manifest{
+		Version:    "1.0.0",
+		Models:     map[string]interface{}{},
+		Transports: []string{s.transport.Name()},
+	}, nil
+}
+
+func handleEcho(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	message, _ := args["message"].(string)
+	return map[string]interface{}{"message": message}, nil
+}
+
+func handleValidate(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	message, ok := args["message"].(string)
+	if !ok || message == "" {
+		return nil, fmt.Errorf("validate: missing required field %q", "message")
+	}
+	return map[string]interface{}{"valid": true}, nil
+}
+
+func handleGetInfo(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	return map[string]interface{}{
+		"implementation": "GoJanus",
+		"startedAt":      time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}, nil
+}
+
+func handleSlowProcess(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	time.Sleep(50 * time.Millisecond)
+	return map[string]interface{}{"done": true}, nil
+}
+
+// lifecycleEvents are the events watch_events tails; see JanusServer.emit.
+var lifecycleEvents = []string{"listening", "draining", "error"}
+
+// handleWatchEvents streams the server's own lifecycle events ("listening",
+// "draining", "error") to the caller as they occur, so external tools can
+// tail server state without polling. It returns a *Stream, so it must be
+// called via Client.Subscribe, which supplies the reply_to address the
+// stream delivers to; a plain SendRequest has no reply_to and will just
+// time out waiting for a response that never comes.
+func (s *JanusServer) handleWatchEvents(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	stream := NewStream()
+	ids := make(map[string]uint64, len(lifecycleEvents))
+	for _, event := range lifecycleEvents {
+		event := event
+		ids[event] = s.On(event, func(data interface{}) {
+			stream.Send(map[string]interface{}{"event": event, "data": data})
+		})
+	}
+	// Without this, the three On closures above would keep firing against
+	// an abandoned stream for the life of the server once the subscriber
+	// is gone; see serveStream's liveness probe for how "gone" is detected
+	// even when no lifecycle event ever arrives to reveal it.
+	stream.OnClose(func() {
+		for event, id := range ids {
+			s.Off(event, id)
+		}
+	})
+	return stream, nil
+}
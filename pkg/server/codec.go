@@ -0,0 +1,152 @@
+package server
+
+import (
+	"encoding/json"
+
+	"GoJanus/pkg/models"
+)
+
+// serverCodec mirrors protocol.Codec from the server's side of the wire:
+// it decodes an incoming request and encodes the matching response using
+// the same format the caller sent, so a single listener can serve both
+// native Janus clients and JSON-RPC 2.0 clients.
+type serverCodec interface {
+	EncodeResponse(resp *models.JanusResponse) ([]byte, error)
+}
+
+type nativeServerCodec struct{}
+
+func (nativeServerCodec) EncodeResponse(resp *models.JanusResponse) ([]byte, error) {
+	return json.Marshal(resp)
+}
+
+type jsonRPC2ServerCodec struct {
+	// id is the JSON-RPC id echoed back from the decoded request, kept as
+	// the raw JSON it arrived as (a string or a number per spec) rather
+	// than coerced to a Go string, so it round-trips verbatim.
+	id json.RawMessage
+}
+
+type jsonRPC2WireError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type jsonRPC2WireResponse struct {
+	JSONRPC string             `json:"jsonrpc"`
+	ID      json.RawMessage    `json:"id"`
+	Result  interface{}        `json:"result,omitempty"`
+	Error   *jsonRPC2WireError `json:"error,omitempty"`
+}
+
+func (c jsonRPC2ServerCodec) EncodeResponse(resp *models.JanusResponse) ([]byte, error) {
+	wire := jsonRPC2WireResponse{JSONRPC: "2.0", ID: c.id}
+	if resp.Error != nil {
+		wire.Error = &jsonRPC2WireError{Code: jsonRPC2ErrorCode(resp.Error.Code), Message: resp.Error.Message}
+	} else {
+		wire.Result = resp.Result
+		if wire.Result == nil {
+			// A bare omitempty would drop "result" entirely for a
+			// no-payload success (e.g. a handler returning nil), leaving
+			// neither "result" nor "error" on the wire, which violates
+			// the spec's "exactly one of result/error" requirement.
+			wire.Result = json.RawMessage("null")
+		}
+	}
+	return json.Marshal(wire)
+}
+
+// jsonRPC2ErrorCode maps a Janus error code to the nearest standard
+// JSON-RPC 2.0 error code (https://www.jsonrpc.org/specification#error_object),
+// so a generic JSON-RPC client can distinguish e.g. an unknown method from
+// an internal error without parsing Message. Codes with no standard
+// equivalent fall into the -32000..-32099 range reserved for
+// implementation-defined server errors.
+func jsonRPC2ErrorCode(code string) int {
+	switch code {
+	case models.ErrCodeUnknownRequest:
+		return -32601 // Method not found
+	case models.ErrCodeInvalidArguments:
+		return -32602 // Invalid params
+	case models.ErrCodeInternal:
+		return -32603 // Internal error
+	case models.ErrCodeTimeout:
+		return -32000
+	case models.ErrCodeServerShuttingDown:
+		return -32001
+	case models.ErrCodePermissionDenied:
+		return -32002
+	default:
+		return -32000
+	}
+}
+
+type jsonRPC2WireRequest struct {
+	JSONRPC string                 `json:"jsonrpc"`
+	ID      json.RawMessage        `json:"id"`
+	Method  string                 `json:"method"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+}
+
+// jsonRPC2IDToString derives the string form of a JanusRequest/JanusResponse
+// id from a raw JSON-RPC id, which per spec may be a string or a number.
+// Janus's native envelope always carries a string id, so this is the one
+// place that bridges the two: a numeric id is rendered as its decimal
+// digits, a string id is unquoted, and a missing/null id becomes "".
+func jsonRPC2IDToString(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var n json.Number
+	if json.Unmarshal(raw, &n) == nil {
+		return n.String()
+	}
+	return ""
+}
+
+// decodeRequest parses an incoming payload into a JanusRequest, detecting
+// whether it arrived as the native Janus envelope or as JSON-RPC 2.0, and
+// returns a serverCodec that will reply in the same format.
+func decodeRequest(payload []byte) (*models.JanusRequest, serverCodec, error) {
+	if sniffJSONRPC2(payload) {
+		var wire jsonRPC2WireRequest
+		if err := json.Unmarshal(payload, &wire); err != nil {
+			return nil, nil, err
+		}
+		req := &models.JanusRequest{
+			ID:      jsonRPC2IDToString(wire.ID),
+			Request: wire.Method,
+			Args:    wire.Params,
+		}
+		return req, jsonRPC2ServerCodec{id: wire.ID}, nil
+	}
+
+	var req models.JanusRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return nil, nil, err
+	}
+	return &req, nativeServerCodec{}, nil
+}
+
+// jsonRPC2InvalidRequestResponse builds a JSON-RPC 2.0 "Invalid Request"
+// error response for a payload that sniffed as JSON-RPC 2.0 (so the peer
+// expects a JSON-RPC reply) but didn't decode into a usable request object.
+// The id is recovered on a best-effort basis, since decodeRequest's own
+// unmarshal already failed; per spec, a null id is used when it can't be
+// determined.
+func jsonRPC2InvalidRequestResponse(payload []byte) []byte {
+	var probe struct {
+		ID json.RawMessage `json:"id"`
+	}
+	id := json.RawMessage("null")
+	if json.Unmarshal(payload, &probe) == nil && len(probe.ID) > 0 {
+		id = probe.ID
+	}
+	out, _ := json.Marshal(jsonRPC2WireResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   &jsonRPC2WireError{Code: -32600, Message: "invalid Request"},
+	})
+	return out
+}
@@ -0,0 +1,26 @@
+package server
+
+import "context"
+
+// PeerCredentials identifies the process on the other end of a Unix
+// domain socket connection, as attested by the kernel rather than
+// anything the peer claims about itself.
+type PeerCredentials struct {
+	UID uint32
+	GID uint32
+	PID int32
+}
+
+type peerContextKey struct{}
+
+func withPeer(ctx context.Context, creds PeerCredentials) context.Context {
+	return context.WithValue(ctx, peerContextKey{}, creds)
+}
+
+// PeerFromContext returns the PeerCredentials the dispatcher attached to
+// ctx for the connection a request arrived on. ok is false if ctx carries
+// no credentials, which happens when the transport couldn't attest them.
+func PeerFromContext(ctx context.Context) (creds PeerCredentials, ok bool) {
+	creds, ok = ctx.Value(peerContextKey{}).(PeerCredentials)
+	return creds, ok
+}
@@ -0,0 +1,35 @@
+//go:build freebsd || netbsd || openbsd
+
+package server
+
+import (
+	"fmt"
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// peerCredentialsFromConn reads the kernel-attested credentials of the
+// process on the other end of a Unix domain stream connection via
+// LOCAL_PEERCRED. Unlike Darwin, these BSDs don't expose the peer's PID
+// through a sockopt, so PID is left zero.
+func peerCredentialsFromConn(conn *net.UnixConn) (PeerCredentials, error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return PeerCredentials{}, err
+	}
+
+	var creds PeerCredentials
+	var opErr error
+	if err := raw.Control(func(fd uintptr) {
+		xucred, err := unix.GetsockoptXucred(int(fd), unix.SOL_LOCAL, unix.LOCAL_PEERCRED)
+		if err != nil {
+			opErr = fmt.Errorf("server: LOCAL_PEERCRED: %w", err)
+			return
+		}
+		creds = PeerCredentials{UID: xucred.Uid, GID: uint32(xucred.Groups[0])}
+	}); err != nil {
+		return PeerCredentials{}, err
+	}
+	return creds, opErr
+}
@@ -0,0 +1,40 @@
+//go:build darwin
+
+package server
+
+import (
+	"fmt"
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// peerCredentialsFromConn reads the kernel-attested credentials of the
+// process on the other end of a Unix domain stream connection.
+// LOCAL_PEERCRED supplies the UID/GID; the Darwin-specific LOCAL_PEERPID
+// option supplies the PID.
+func peerCredentialsFromConn(conn *net.UnixConn) (PeerCredentials, error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return PeerCredentials{}, err
+	}
+
+	var creds PeerCredentials
+	var opErr error
+	if err := raw.Control(func(fd uintptr) {
+		xucred, err := unix.GetsockoptXucred(int(fd), unix.SOL_LOCAL, unix.LOCAL_PEERCRED)
+		if err != nil {
+			opErr = fmt.Errorf("server: LOCAL_PEERCRED: %w", err)
+			return
+		}
+		pid, err := unix.GetsockoptInt(int(fd), unix.SOL_LOCAL, unix.LOCAL_PEERPID)
+		if err != nil {
+			opErr = fmt.Errorf("server: LOCAL_PEERPID: %w", err)
+			return
+		}
+		creds = PeerCredentials{UID: xucred.Uid, GID: uint32(xucred.Groups[0]), PID: int32(pid)}
+	}); err != nil {
+		return PeerCredentials{}, err
+	}
+	return creds, opErr
+}
@@ -0,0 +1,32 @@
+//go:build linux
+
+package server
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// peerCredentialsFromConn reads the kernel-attested credentials of the
+// process on the other end of a Unix domain stream connection via
+// SO_PEERCRED.
+func peerCredentialsFromConn(conn *net.UnixConn) (PeerCredentials, error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return PeerCredentials{}, err
+	}
+
+	var ucred *syscall.Ucred
+	var opErr error
+	if err := raw.Control(func(fd uintptr) {
+		ucred, opErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	}); err != nil {
+		return PeerCredentials{}, err
+	}
+	if opErr != nil {
+		return PeerCredentials{}, fmt.Errorf("server: SO_PEERCRED: %w", opErr)
+	}
+
+	return PeerCredentials{UID: ucred.Uid, GID: ucred.Gid, PID: ucred.Pid}, nil
+}
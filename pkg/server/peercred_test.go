@@ -0,0 +1,250 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"GoJanus/pkg/protocol"
+	"GoJanus/pkg/server"
+)
+
+func startTestServer(t *testing.T, cfg *server.ServerConfig) *server.JanusServer {
+	t.Helper()
+	srv := server.NewJanusServer(cfg)
+
+	ready := make(chan bool, 1)
+	srv.On("listening", func(data interface{}) {
+		select {
+		case ready <- true:
+		default:
+		}
+	})
+	go srv.StartListening()
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server startup timeout")
+	}
+	return srv
+}
+
+func TestPeerFromContextMatchesCallingProcess(t *testing.T) {
+	socketPath := fmt.Sprintf("/tmp/go-peercred-test-%d.sock", time.Now().UnixNano())
+	defer os.Remove(socketPath)
+
+	var gotCreds server.PeerCredentials
+	var gotOK bool
+	seen := make(chan struct{})
+
+	srv := server.NewJanusServer(&server.ServerConfig{SocketPath: socketPath})
+	srv.Handle("whoami", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		gotCreds, gotOK = server.PeerFromContext(ctx)
+		close(seen)
+		return map[string]interface{}{"ok": true}, nil
+	})
+
+	ready := make(chan bool, 1)
+	srv.On("listening", func(data interface{}) {
+		select {
+		case ready <- true:
+		default:
+		}
+	})
+	go srv.StartListening()
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server startup timeout")
+	}
+
+	client, err := protocol.New(socketPath)
+	if err != nil {
+		t.Fatalf("protocol.New: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.SendRequest(ctx, "whoami", nil); err != nil {
+		t.Fatalf("SendRequest: %v", err)
+	}
+
+	select {
+	case <-seen:
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler never ran")
+	}
+
+	if !gotOK {
+		t.Fatal("expected PeerFromContext to report credentials for a Unix socket connection")
+	}
+	// The client dials from this same process, so the kernel-attested UID
+	// must match ours; PID can differ across sandboxed test runners that
+	// remap PID namespaces per exec, so it is not asserted here.
+	if gotCreds.UID != uint32(os.Getuid()) {
+		t.Fatalf("expected peer uid %d (same process, loopback client), got %d", os.Getuid(), gotCreds.UID)
+	}
+}
+
+// childUID is an arbitrary non-root uid (the conventional "daemon" uid on
+// Linux) that the test process, running as root, re-execs the helper
+// process under, so the kernel attests a caller that is neither this test
+// process's uid nor pid.
+const childUID = 1
+
+// TestPeerFromContextMatchesForeignProcess verifies the core claim behind
+// SO_PEERCRED/LOCAL_PEERCRED: that the credentials PeerFromContext reports
+// are the kernel's attestation of the *actual* calling process, not
+// anything derivable from the connecting process alone. It re-execs this
+// test binary as TestHelperProcess under a different uid and checks both
+// the uid and the pid the server observed match the child, which
+// TestPeerFromContextMatchesCallingProcess (same process as the server)
+// can't exercise.
+func TestPeerFromContextMatchesForeignProcess(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("requires root to exec a helper process under a different uid")
+	}
+
+	socketPath := fmt.Sprintf("/tmp/go-peercred-foreign-test-%d.sock", time.Now().UnixNano())
+	defer os.Remove(socketPath)
+
+	var gotCreds server.PeerCredentials
+	var gotOK bool
+	seen := make(chan struct{})
+
+	srv := server.NewJanusServer(&server.ServerConfig{SocketPath: socketPath})
+	srv.Handle("whoami", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		gotCreds, gotOK = server.PeerFromContext(ctx)
+		close(seen)
+		return map[string]interface{}{"ok": true}, nil
+	})
+
+	ready := make(chan bool, 1)
+	srv.On("listening", func(data interface{}) {
+		select {
+		case ready <- true:
+		default:
+		}
+	})
+	go srv.StartListening()
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server startup timeout")
+	}
+	// The socket is created by this (root) test process; relax its mode so
+	// the unprivileged helper process below can connect to it. The test
+	// binary and the directory `go test` built it into also need to be
+	// world-executable, since that directory is normally only traversable
+	// by its (root) owner.
+	if err := os.Chmod(socketPath, 0666); err != nil {
+		t.Fatalf("chmod socket: %v", err)
+	}
+	if err := os.Chmod(os.Args[0], 0755); err != nil {
+		t.Fatalf("chmod test binary: %v", err)
+	}
+	allowExecTraversal(t, os.Args[0])
+
+	cmd := exec.Command(os.Args[0], "-test.run=TestHelperProcess")
+	cmd.Env = append(os.Environ(), "GO_WANT_HELPER_PROCESS=1", "GO_HELPER_SOCKET_PATH="+socketPath)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Credential: &syscall.Credential{Uid: childUID, Gid: childUID}}
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("helper process: %v", err)
+	}
+	childPID := cmd.Process.Pid
+
+	select {
+	case <-seen:
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler never ran")
+	}
+
+	if !gotOK {
+		t.Fatal("expected PeerFromContext to report credentials for the helper process's connection")
+	}
+	if gotCreds.UID != childUID {
+		t.Fatalf("expected peer uid %d (the helper process), got %d", childUID, gotCreds.UID)
+	}
+	if gotCreds.PID != int32(childPID) {
+		t.Fatalf("expected peer pid %d (the helper process), got %d", childPID, gotCreds.PID)
+	}
+}
+
+// allowExecTraversal grants "other" execute permission on path's ancestor
+// directories, up to the first one that already has it. `go test` builds
+// the test binary into a directory only its (root) owner can traverse,
+// which would otherwise stop the unprivileged helper process's exec from
+// even reaching the binary's permission bits.
+func allowExecTraversal(t *testing.T, path string) {
+	t.Helper()
+	dir := filepath.Dir(path)
+	for dir != "/" && dir != "." {
+		info, err := os.Stat(dir)
+		if err != nil {
+			t.Fatalf("stat %s: %v", dir, err)
+		}
+		if info.Mode().Perm()&0001 == 0 {
+			if err := os.Chmod(dir, info.Mode().Perm()|0001); err != nil {
+				t.Fatalf("chmod %s: %v", dir, err)
+			}
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// TestHelperProcess isn't a real test; TestPeerFromContextMatchesForeignProcess
+// re-execs this binary as a subprocess (via os.Args[0]) to produce a request
+// from a process with its own, different uid and pid. GO_WANT_HELPER_PROCESS
+// gates it so a normal `go test` run treats it as a no-op.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	client, err := protocol.New(os.Getenv("GO_HELPER_SOCKET_PATH"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "protocol.New:", err)
+		os.Exit(1)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.SendRequest(ctx, "whoami", nil); err != nil {
+		fmt.Fprintln(os.Stderr, "SendRequest:", err)
+		os.Exit(1)
+	}
+}
+
+func TestAuthorizerDeniesRequest(t *testing.T) {
+	socketPath := fmt.Sprintf("/tmp/go-authz-test-%d.sock", time.Now().UnixNano())
+	defer os.Remove(socketPath)
+
+	srv := startTestServer(t, &server.ServerConfig{
+		SocketPath: socketPath,
+		Authorizer: func(creds server.PeerCredentials, request string) error {
+			return fmt.Errorf("uid %d not allowed to call %q", creds.UID, request)
+		},
+	})
+	_ = srv
+
+	client, err := protocol.New(socketPath)
+	if err != nil {
+		t.Fatalf("protocol.New: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.SendRequest(ctx, "ping", nil)
+	if err != nil {
+		t.Fatalf("SendRequest: %v", err)
+	}
+	if resp.Success || resp.Error == nil || resp.Error.Code != "PERMISSION_DENIED" {
+		t.Fatalf("expected PERMISSION_DENIED, got %+v", resp)
+	}
+}
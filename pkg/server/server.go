@@ -0,0 +1,429 @@
+// Package server implements the Janus request dispatcher: a listener over
+// a pluggable transport.Transport that decodes requests, routes them to
+// registered handlers, and writes back the response.
+package server
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"GoJanus/pkg/models"
+	"GoJanus/pkg/transport"
+)
+
+// HandlerFunc processes a single request's arguments and returns a result
+// (marshaled into JanusResponse.Result) or an error. ctx carries the
+// calling peer's credentials, retrievable with PeerFromContext.
+type HandlerFunc func(ctx context.Context, args map[string]interface{}) (interface{}, error)
+
+// EventHandler receives server lifecycle notifications emitted via On/emit.
+type EventHandler func(data interface{})
+
+// ServerConfig configures a JanusServer.
+type ServerConfig struct {
+	// SocketPath is the Unix domain socket the server listens on, used
+	// when Transport is nil. Ignored if Transport is set.
+	SocketPath string
+	// Transport selects what the server listens over. Defaults to
+	// transport.Unix{SocketPath: SocketPath} when nil.
+	Transport transport.Transport
+	// LameDuckDuration bounds how long Shutdown waits for in-flight
+	// requests to finish after draining begins. Zero means Shutdown
+	// returns as soon as draining starts, without waiting.
+	LameDuckDuration time.Duration
+	// Authorizer, if set, is consulted with the caller's peer credentials
+	// before a request is dispatched. A non-nil error fails the request
+	// with a PERMISSION_DENIED response instead of invoking its handler.
+	Authorizer func(PeerCredentials, string) error
+}
+
+// JanusServer listens over a transport.Transport, decodes requests, and
+// dispatches them to registered handlers.
+type JanusServer struct {
+	config    *ServerConfig
+	transport transport.Transport
+	handlers  map[string]HandlerFunc
+
+	mu           sync.Mutex
+	listeners    map[string][]eventListener
+	nextListener uint64
+
+	listenerMu sync.Mutex
+	listener   net.Listener
+
+	draining atomic.Bool
+	inFlight sync.WaitGroup
+
+	connsMu sync.Mutex
+	conns   map[net.Conn]struct{}
+}
+
+// NewJanusServer creates a server bound to config.SocketPath, pre-registered
+// with the built-in diagnostic requests (ping, manifest, echo, validate,
+// get_info, slow_process).
+func NewJanusServer(config *ServerConfig) *JanusServer {
+	t := config.Transport
+	if t == nil {
+		t = transport.Unix{SocketPath: config.SocketPath}
+	}
+	s := &JanusServer{
+		config:    config,
+		transport: t,
+		handlers:  make(map[string]HandlerFunc),
+		listeners: make(map[string][]eventListener),
+		conns:     make(map[net.Conn]struct{}),
+	}
+	s.registerBuiltins()
+	return s
+}
+
+// eventListener is one registration made via On, identified by id so Off
+// can remove it from the middle of s.listeners[event] without disturbing
+// the others.
+type eventListener struct {
+	id uint64
+	fn EventHandler
+}
+
+// On registers handler to be invoked whenever event is emitted, and
+// returns an id that can be passed to Off to deregister it. Recognized
+// lifecycle events include "listening", "draining", and "error".
+func (s *JanusServer) On(event string, handler EventHandler) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextListener++
+	id := s.nextListener
+	s.listeners[event] = append(s.listeners[event], eventListener{id: id, fn: handler})
+	return id
+}
+
+// Off removes the listener id (as returned by On) from event. It is a
+// no-op if id is not currently registered for event, so a caller that
+// tears down a subscription is free to call it more than once. Long-lived
+// handlers (such as the built-in watch_events) must call this once their
+// subscriber is gone, or the closure stays in s.listeners and keeps
+// firing against an abandoned stream forever.
+func (s *JanusServer) Off(event string, id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hs := s.listeners[event]
+	for i, h := range hs {
+		if h.id == id {
+			s.listeners[event] = append(hs[:i:i], hs[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *JanusServer) emit(event string, data interface{}) {
+	s.mu.Lock()
+	handlers := append([]eventListener(nil), s.listeners[event]...)
+	s.mu.Unlock()
+	for _, h := range handlers {
+		h.fn(data)
+	}
+}
+
+// Handle registers (or replaces) the handler for a request name.
+func (s *JanusServer) Handle(request string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[request] = handler
+}
+
+// StartListening binds the configured socket and serves requests until the
+// listener is closed (either because Accept failed or Shutdown was
+// called). It emits "listening" once the socket is bound and "error" if
+// accepting fails for a reason other than a requested shutdown.
+func (s *JanusServer) StartListening() error {
+	listener, err := s.transport.Listen()
+	if err != nil {
+		s.emit("error", err)
+		return fmt.Errorf("server: listen: %w", err)
+	}
+	s.listenerMu.Lock()
+	s.listener = listener
+	s.listenerMu.Unlock()
+	defer listener.Close()
+
+	s.emit("listening", s.config.SocketPath)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if s.draining.Load() {
+				return nil
+			}
+			s.emit("error", err)
+			return err
+		}
+
+		s.trackConn(conn)
+		s.inFlight.Add(1)
+		go func() {
+			defer s.inFlight.Done()
+			defer s.untrackConn(conn)
+			s.handleConn(conn)
+		}()
+	}
+}
+
+// Shutdown begins a graceful, lame-duck shutdown: it emits "draining" so
+// freshly arriving requests start getting a SERVER_SHUTTING_DOWN response
+// instead of being dispatched, then waits for requests already in flight
+// to finish, up to config.LameDuckDuration (or until ctx is done). Either
+// way, once Shutdown returns, the listener and any remaining connections
+// have been closed. If the deadline is reached first, Shutdown closes
+// everything forcibly and returns context.DeadlineExceeded.
+func (s *JanusServer) Shutdown(ctx context.Context) error {
+	s.draining.Store(true)
+	s.emit("draining", nil)
+
+	closeListener := func() {
+		s.listenerMu.Lock()
+		l := s.listener
+		s.listenerMu.Unlock()
+		if l != nil {
+			l.Close()
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.inFlight.Wait()
+		close(done)
+	}()
+
+	var deadline <-chan time.Time
+	if s.config.LameDuckDuration > 0 {
+		timer := time.NewTimer(s.config.LameDuckDuration)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	select {
+	case <-done:
+		closeListener()
+		return nil
+	case <-deadline:
+		closeListener()
+		s.forceCloseConns()
+		return context.DeadlineExceeded
+	case <-ctx.Done():
+		closeListener()
+		s.forceCloseConns()
+		return ctx.Err()
+	}
+}
+
+func (s *JanusServer) trackConn(conn net.Conn) {
+	s.connsMu.Lock()
+	s.conns[conn] = struct{}{}
+	s.connsMu.Unlock()
+}
+
+func (s *JanusServer) untrackConn(conn net.Conn) {
+	s.connsMu.Lock()
+	delete(s.conns, conn)
+	s.connsMu.Unlock()
+}
+
+func (s *JanusServer) forceCloseConns() {
+	s.connsMu.Lock()
+	defer s.connsMu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+func (s *JanusServer) handleConn(conn net.Conn) {
+	defer conn.Close()
+
+	ctx := context.Background()
+	switch c := conn.(type) {
+	case *net.UnixConn:
+		if creds, err := peerCredentialsFromConn(c); err == nil {
+			ctx = withPeer(ctx, creds)
+		}
+	case transport.CredentialedConn:
+		// Datagram transports (e.g. transport.UnixDatagram) hand back a
+		// one-shot conn per packet rather than a *net.UnixConn, so their
+		// credentials (where the platform supports them) arrive this way
+		// instead of via peerCredentialsFromConn.
+		if creds, ok := c.PeerCredentials(); ok {
+			ctx = withPeer(ctx, PeerCredentials{UID: creds.UID, GID: creds.GID, PID: creds.PID})
+		}
+	}
+
+	payload, err := readMessage(conn, s.transport.Framing())
+	if err != nil {
+		return
+	}
+
+	req, codec, err := decodeRequest(payload)
+	if err != nil {
+		// A payload that sniffs as JSON-RPC 2.0 implies a peer expecting a
+		// JSON-RPC reply even when the request itself didn't decode; any
+		// other malformed payload has no envelope to reply in, so it's
+		// dropped as before.
+		if sniffJSONRPC2(payload) {
+			writeMessage(conn, s.transport.Framing(), jsonRPC2InvalidRequestResponse(payload))
+		}
+		return
+	}
+
+	var resp *models.JanusResponse
+	var stream *Stream
+	if s.draining.Load() {
+		resp = shuttingDownResponse(req)
+	} else {
+		resp, stream = s.dispatch(ctx, req)
+	}
+
+	if stream != nil {
+		// The stream delivers to req.ReplyTo on its own schedule, not over
+		// this connection, so it outlives handleConn; it is deliberately
+		// not tracked in s.inFlight, since a long-lived subscription
+		// shouldn't hold up Shutdown's lame-duck drain.
+		go s.serveStream(req, stream)
+		return
+	}
+
+	out, err := codec.EncodeResponse(resp)
+	if err != nil {
+		return
+	}
+	writeMessage(conn, s.transport.Framing(), out)
+}
+
+func shuttingDownResponse(req *models.JanusRequest) *models.JanusResponse {
+	return &models.JanusResponse{
+		RequestID: req.ID,
+		ID:        req.ID,
+		Success:   false,
+		Error:     &models.JanusError{Code: models.ErrCodeServerShuttingDown, Message: "server is draining and not accepting new requests"},
+		Timestamp: time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+}
+
+// dispatch invokes the handler registered for req.Request and builds its
+// response. If the handler returns a *Stream instead of a plain result,
+// dispatch returns it as the second value and a nil response; the caller
+// is then responsible for draining the stream to req.ReplyTo instead of
+// writing a response to the connection.
+func (s *JanusServer) dispatch(ctx context.Context, req *models.JanusRequest) (*models.JanusResponse, *Stream) {
+	s.mu.Lock()
+	handler, ok := s.handlers[req.Request]
+	s.mu.Unlock()
+
+	now := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+	if !ok {
+		return &models.JanusResponse{
+			RequestID: req.ID,
+			ID:        req.ID,
+			Success:   false,
+			Error:     &models.JanusError{Code: models.ErrCodeUnknownRequest, Message: fmt.Sprintf("unknown request %q", req.Request)},
+			Timestamp: now,
+		}, nil
+	}
+
+	if s.config.Authorizer != nil {
+		creds, _ := PeerFromContext(ctx)
+		if err := s.config.Authorizer(creds, req.Request); err != nil {
+			return &models.JanusResponse{
+				RequestID: req.ID,
+				ID:        req.ID,
+				Success:   false,
+				Error:     &models.JanusError{Code: models.ErrCodePermissionDenied, Message: err.Error()},
+				Timestamp: now,
+			}, nil
+		}
+	}
+
+	result, err := handler(ctx, req.Args)
+	if err != nil {
+		return &models.JanusResponse{
+			RequestID: req.ID,
+			ID:        req.ID,
+			Success:   false,
+			Error:     &models.JanusError{Code: models.ErrCodeInternal, Message: err.Error()},
+			Timestamp: now,
+		}, nil
+	}
+
+	if stream, ok := result.(*Stream); ok {
+		return nil, stream
+	}
+
+	return &models.JanusResponse{
+		RequestID: req.ID,
+		ID:        req.ID,
+		Success:   true,
+		Result:    result,
+		Timestamp: now,
+	}, nil
+}
+
+// maxPacketMessageSize bounds a single read on a packet-framed transport
+// (see transport.FramingPacket), mirroring pkg/protocol's client-side limit.
+const maxPacketMessageSize = 64 * 1024
+
+func writeMessage(w io.Writer, framing transport.Framing, payload []byte) error {
+	if framing == transport.FramingPacket {
+		_, err := w.Write(payload)
+		return err
+	}
+	return writeFrame(w, payload)
+}
+
+func readMessage(r io.Reader, framing transport.Framing) ([]byte, error) {
+	if framing == transport.FramingPacket {
+		buf := make([]byte, maxPacketMessageSize)
+		n, err := r.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	}
+	return readFrame(r)
+}
+
+func writeFrame(w io.Writer, payload []byte) error {
+	if err := binary.Write(w, binary.BigEndian, uint32(len(payload))); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+func readFrame(r io.Reader) ([]byte, error) {
+	var length uint32
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// sniffJSONRPC2 reports whether payload looks like a JSON-RPC 2.0 request
+// (as opposed to the native Janus envelope), so the dispatcher can decode
+// and reply using the same wire format the caller used.
+func sniffJSONRPC2(payload []byte) bool {
+	var probe struct {
+		JSONRPC string `json:"jsonrpc"`
+	}
+	if err := json.Unmarshal(payload, &probe); err != nil {
+		return false
+	}
+	return probe.JSONRPC == "2.0"
+}
@@ -0,0 +1,108 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"GoJanus/pkg/protocol"
+	"GoJanus/pkg/server"
+)
+
+func TestShutdownRejectsNewRequestsAndWaitsForInFlight(t *testing.T) {
+	socketPath := fmt.Sprintf("/tmp/go-shutdown-test-%d.sock", time.Now().UnixNano())
+	defer os.Remove(socketPath)
+
+	srv := server.NewJanusServer(&server.ServerConfig{SocketPath: socketPath, LameDuckDuration: time.Second})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	srv.Handle("slow", func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+		close(started)
+		<-release
+		return "done", nil
+	})
+
+	ready := make(chan bool, 1)
+	srv.On("listening", func(data interface{}) {
+		select {
+		case ready <- true:
+		default:
+		}
+	})
+	draining := make(chan bool, 1)
+	srv.On("draining", func(data interface{}) {
+		select {
+		case draining <- true:
+		default:
+		}
+	})
+
+	go srv.StartListening()
+	select {
+	case <-ready:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server startup timeout")
+	}
+
+	client, err := protocol.New(socketPath)
+	if err != nil {
+		t.Fatalf("protocol.New: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		resp, err := client.SendRequest(ctx, "slow", nil)
+		if err != nil {
+			done <- err
+			return
+		}
+		if !resp.Success {
+			done <- fmt.Errorf("expected success, got %+v", resp)
+			return
+		}
+		done <- nil
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler never started")
+	}
+
+	shutdownDone := make(chan error, 1)
+	go func() {
+		shutdownDone <- srv.Shutdown(context.Background())
+	}()
+
+	select {
+	case <-draining:
+	case <-time.After(5 * time.Second):
+		t.Fatal("draining event never emitted")
+	}
+
+	// A request arriving while draining should be rejected immediately.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rejected, err := client.SendRequest(ctx, "ping", nil)
+	if err != nil {
+		t.Fatalf("SendRequest: %v", err)
+	}
+	if rejected.Success || rejected.Error == nil || rejected.Error.Code != "SERVER_SHUTTING_DOWN" {
+		t.Fatalf("expected SERVER_SHUTTING_DOWN, got %+v", rejected)
+	}
+
+	close(release)
+
+	if err := <-done; err != nil {
+		t.Fatalf("in-flight request failed: %v", err)
+	}
+
+	if err := <-shutdownDone; err != nil {
+		t.Fatalf("Shutdown returned error: %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+
+	"GoJanus/pkg/models"
+)
+
+// streamBufferSize bounds how many pending values a Stream holds before
+// Send starts dropping rather than blocking its caller (typically the
+// event bus's emit, for the watch_events builtin).
+const streamBufferSize = 64
+
+// maxConsecutiveSendFailures bounds how many consecutive ENOENT/
+// ECONNREFUSED deliveries to a subscriber's reply_to address are
+// tolerated before its stream is cancelled, on the assumption the
+// subscribing process has exited.
+const maxConsecutiveSendFailures = 3
+
+// streamLivenessProbeInterval bounds how long serveStream can sit idle
+// waiting for a value before it probes the subscriber directly. Without
+// this, a stream fed by infrequent events (such as watch_events, which
+// only sends when the server's own lifecycle changes) would never notice
+// a crashed subscriber: the ENOENT/ECONNREFUSED detection in
+// sendStreamResponse only runs when there's something to send. A var
+// rather than a const so tests can shrink it instead of waiting out the
+// production interval.
+var streamLivenessProbeInterval = 2 * time.Second
+
+// Stream lets a HandlerFunc emit a sequence of responses to a request
+// whose caller subscribed via Client.Subscribe, instead of returning a
+// single value. Create one with NewStream and return it as the handler's
+// result; send values for it from a goroutine, then call Close (or Fail)
+// once there are no more.
+type Stream struct {
+	values  chan interface{}
+	err     error
+	onClose func()
+}
+
+// NewStream creates an empty Stream ready to Send on.
+func NewStream() *Stream {
+	return &Stream{values: make(chan interface{}, streamBufferSize)}
+}
+
+// OnClose registers fn to run once serveStream stops delivering this
+// stream, whether because the handler closed it (Close/Fail) or because
+// the subscriber appears to have gone away. A handler that registers
+// external resources to feed the stream (such as watch_events's event
+// listeners) should release them here instead of leaking until the
+// server itself shuts down.
+func (s *Stream) OnClose(fn func()) {
+	s.onClose = fn
+}
+
+// Send emits value as the next streamed response. If the buffer is full
+// (a slow or gone subscriber), the value is dropped rather than blocking
+// the caller.
+func (s *Stream) Send(value interface{}) {
+	select {
+	case s.values <- value:
+	default:
+	}
+}
+
+// Close terminates the stream successfully; the subscriber's final
+// message carries done:true.
+func (s *Stream) Close() {
+	close(s.values)
+}
+
+// Fail terminates the stream with an error, delivered to the subscriber
+// as the final message's error instead of a plain done:true.
+func (s *Stream) Fail(err error) {
+	s.err = err
+	close(s.values)
+}
+
+// serveStream drains stream, delivering each value to req.ReplyTo as a
+// JanusResponse with a monotonically increasing seq, followed by a final
+// message carrying done:true (or an error if the stream failed). It gives
+// up once the subscriber has missed maxConsecutiveSendFailures deliveries
+// in a row, whether those were real values or the periodic liveness
+// probe that stands in for one when the stream sits idle. Either way,
+// stream.OnClose's callback (if any) always runs before serveStream
+// returns, so a handler's resources never outlive the stream.
+func (s *JanusServer) serveStream(req *models.JanusRequest, stream *Stream) {
+	if stream.onClose != nil {
+		defer stream.onClose()
+	}
+
+	if req.ReplyTo == nil {
+		stream.Close()
+		return
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: *req.ReplyTo, Net: "unixgram"})
+	if err != nil {
+		s.emit("error", fmt.Errorf("server: stream dial reply_to %s: %w", *req.ReplyTo, err))
+		return
+	}
+	defer conn.Close()
+
+	probe := time.NewTicker(streamLivenessProbeInterval)
+	defer probe.Stop()
+
+	seq := 0
+	failures := 0
+	for {
+		select {
+		case value, ok := <-stream.values:
+			if !ok {
+				seqVal := seq
+				final := &models.JanusResponse{
+					RequestID: req.ID,
+					ID:        req.ID,
+					Success:   stream.err == nil,
+					Seq:       &seqVal,
+					Done:      true,
+					Timestamp: now(),
+				}
+				if stream.err != nil {
+					final.Error = &models.JanusError{Code: models.ErrCodeInternal, Message: stream.err.Error()}
+				}
+				sendStreamResponse(conn, final, &failures)
+				return
+			}
+			seqVal := seq
+			resp := &models.JanusResponse{
+				RequestID: req.ID,
+				ID:        req.ID,
+				Success:   true,
+				Result:    value,
+				Seq:       &seqVal,
+				Timestamp: now(),
+			}
+			if !sendStreamResponse(conn, resp, &failures) {
+				return
+			}
+			seq++
+		case <-probe.C:
+			if !probeStreamLiveness(conn, &failures) {
+				return
+			}
+		}
+	}
+}
+
+// sendStreamResponse writes resp to conn, reporting whether the stream
+// should continue: a hard encode/write error stops it immediately, while
+// an ENOENT/ECONNREFUSED write error (the subscriber's socket is gone)
+// only stops it once *failures reaches maxConsecutiveSendFailures.
+func sendStreamResponse(conn *net.UnixConn, resp *models.JanusResponse, failures *int) bool {
+	payload, err := json.Marshal(resp)
+	if err != nil {
+		return true
+	}
+	_, err = conn.Write(payload)
+	return recordStreamWrite(err, failures)
+}
+
+// probeStreamLiveness writes a zero-length datagram to conn, purely to
+// provoke the same ENOENT/ECONNREFUSED a real send would see if the
+// subscriber is gone. protocol.Client.Subscribe's read loop silently
+// discards any datagram it can't unmarshal as a JanusResponse (an empty
+// one included), so a live subscriber never notices these.
+func probeStreamLiveness(conn *net.UnixConn, failures *int) bool {
+	_, err := conn.Write(nil)
+	return recordStreamWrite(err, failures)
+}
+
+// recordStreamWrite interprets the result of a write to a stream's
+// subscriber, shared by sendStreamResponse and probeStreamLiveness.
+func recordStreamWrite(err error, failures *int) bool {
+	if err != nil {
+		if errors.Is(err, syscall.ENOENT) || errors.Is(err, syscall.ECONNREFUSED) {
+			*failures++
+			return *failures < maxConsecutiveSendFailures
+		}
+		return false
+	}
+	*failures = 0
+	return true
+}
+
+func now() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+}
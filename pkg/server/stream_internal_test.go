@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"GoJanus/pkg/models"
+)
+
+// TestWatchEventsDeregistersListenersOnSubscriberGone verifies that once a
+// watch_events subscriber's reply_to socket disappears, serveStream's
+// liveness probe detects it within a few probe intervals and removes the
+// event listeners handleWatchEvents registered, instead of leaking them
+// in s.listeners for the life of the server.
+func TestWatchEventsDeregistersListenersOnSubscriberGone(t *testing.T) {
+	orig := streamLivenessProbeInterval
+	streamLivenessProbeInterval = 10 * time.Millisecond
+	defer func() { streamLivenessProbeInterval = orig }()
+
+	replyPath := fmt.Sprintf("/tmp/go-watchevents-leak-test-%d.sock", time.Now().UnixNano())
+	replyConn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: replyPath, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("ListenUnixgram: %v", err)
+	}
+	// Simulate a subscriber that crashed: its socket is gone before the
+	// server ever gets to write anything to it.
+	replyConn.Close()
+	os.Remove(replyPath)
+
+	srv := NewJanusServer(&ServerConfig{SocketPath: "/tmp/go-watchevents-leak-unused.sock"})
+
+	result, err := srv.handleWatchEvents(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("handleWatchEvents: %v", err)
+	}
+	stream, ok := result.(*Stream)
+	if !ok {
+		t.Fatalf("expected *Stream, got %T", result)
+	}
+
+	for _, event := range lifecycleEvents {
+		if n := len(srv.listeners[event]); n != 1 {
+			t.Fatalf("expected one listener on %q before serving the stream, got %d", event, n)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		srv.serveStream(&models.JanusRequest{ID: "1", Request: "watch_events", ReplyTo: &replyPath}, stream)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("serveStream never returned for a gone subscriber")
+	}
+
+	for _, event := range lifecycleEvents {
+		if n := len(srv.listeners[event]); n != 0 {
+			t.Fatalf("expected handleWatchEvents's listener on %q to be deregistered, got %d still registered", event, n)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+//go:build linux
+
+package transport
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+// enablePassCred turns on SO_PASSCRED for a Unix datagram socket so the
+// kernel attaches an SCM_CREDENTIALS ancillary message to every datagram
+// the peer sends, which readPacket then extracts.
+func enablePassCred(conn *net.UnixConn) error {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var opErr error
+	if err := raw.Control(func(fd uintptr) {
+		opErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_PASSCRED, 1)
+	}); err != nil {
+		return err
+	}
+	return opErr
+}
+
+// readPacket reads one datagram from conn along with the sender's
+// kernel-attested credentials, for a *net.UnixConn that has had
+// enablePassCred applied (see UnixDatagram.Listen). Other net.PacketConn
+// implementations fall back to a plain ReadFrom with hasCreds false.
+func readPacket(conn net.PacketConn, buf []byte) (n int, addr net.Addr, creds PeerCredentials, hasCreds bool, err error) {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		n, addr, err = conn.ReadFrom(buf)
+		return n, addr, PeerCredentials{}, false, err
+	}
+
+	oob := make([]byte, syscall.CmsgSpace(syscall.SizeofUcred))
+	n, oobn, _, remote, err := unixConn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		return 0, nil, PeerCredentials{}, false, err
+	}
+
+	messages, perr := syscall.ParseSocketControlMessage(oob[:oobn])
+	if perr != nil {
+		return n, remote, PeerCredentials{}, false, fmt.Errorf("transport: parse control message: %w", perr)
+	}
+	for _, msg := range messages {
+		ucred, err := syscall.ParseUnixCredentials(&msg)
+		if err != nil {
+			continue
+		}
+		return n, remote, PeerCredentials{UID: ucred.Uid, GID: ucred.Gid, PID: ucred.Pid}, true, nil
+	}
+	return n, remote, PeerCredentials{}, false, nil
+}
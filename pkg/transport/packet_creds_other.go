@@ -0,0 +1,18 @@
+//go:build !linux
+
+package transport
+
+import "net"
+
+// enablePassCred is a no-op outside Linux: datagram peer credentials
+// (SO_PASSCRED/SCM_CREDENTIALS) are a Linux-specific mechanism, unlike
+// SO_PEERCRED/LOCAL_PEERCRED for stream sockets, which BSD and Darwin
+// also support (see peercred_bsd.go, peercred_darwin.go in pkg/server).
+func enablePassCred(conn *net.UnixConn) error { return nil }
+
+// readPacket falls back to a plain ReadFrom with no attested credentials
+// outside Linux.
+func readPacket(conn net.PacketConn, buf []byte) (n int, addr net.Addr, creds PeerCredentials, hasCreds bool, err error) {
+	n, addr, err = conn.ReadFrom(buf)
+	return n, addr, PeerCredentials{}, false, err
+}
@@ -0,0 +1,105 @@
+package transport
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"time"
+)
+
+// maxDatagramSize bounds a single request/response datagram. Janus
+// requests are small JSON envelopes, so this is generous headroom rather
+// than a tight limit.
+const maxDatagramSize = 64 * 1024
+
+// PeerCredentials identifies the process on the other end of a
+// connectionless socket, as attested by the kernel rather than anything
+// the peer claims about itself. It is only populated for transports that
+// support it; see CredentialedConn.
+type PeerCredentials struct {
+	UID uint32
+	GID uint32
+	PID int32
+}
+
+// CredentialedConn is implemented by a net.Conn that can report the
+// kernel-attested identity of its peer outside of a *net.UnixConn type
+// assertion. pkg/server uses that assertion for stream connections, but
+// a packetConn wraps a shared, connectionless socket, so its per-datagram
+// credentials (where the platform supports them) are surfaced this way
+// instead.
+type CredentialedConn interface {
+	// PeerCredentials returns the credentials attested for this conn's
+	// datagram, and false if the platform or transport couldn't attest
+	// them.
+	PeerCredentials() (PeerCredentials, bool)
+}
+
+// packetListener adapts a connectionless net.PacketConn into a
+// net.Listener: each Accept reads one inbound datagram and hands back a
+// net.Conn scoped to replying to that datagram's sender. This lets the
+// same dispatcher (pkg/server) serve both stream and packet transports.
+type packetListener struct {
+	conn net.PacketConn
+}
+
+func newPacketListener(conn net.PacketConn) net.Listener {
+	return &packetListener{conn: conn}
+}
+
+func (p *packetListener) Accept() (net.Conn, error) {
+	buf := make([]byte, maxDatagramSize)
+	n, remote, creds, hasCreds, err := readPacket(p.conn, buf)
+	if err != nil {
+		return nil, err
+	}
+	return &packetConn{conn: p.conn, remote: remote, pending: bytes.NewReader(buf[:n]), creds: creds, hasCreds: hasCreds}, nil
+}
+
+func (p *packetListener) Close() error   { return p.conn.Close() }
+func (p *packetListener) Addr() net.Addr { return p.conn.LocalAddr() }
+
+// packetConn is a net.Conn backed by a single already-read datagram and
+// the PacketConn to send its reply on. It is one-shot: Read yields the
+// datagram that produced it exactly once, Write sends exactly one reply
+// datagram to the sender.
+type packetConn struct {
+	conn     net.PacketConn
+	remote   net.Addr
+	pending  *bytes.Reader
+	creds    PeerCredentials
+	hasCreds bool
+}
+
+// PeerCredentials implements CredentialedConn.
+func (c *packetConn) PeerCredentials() (PeerCredentials, bool) {
+	return c.creds, c.hasCreds
+}
+
+func (c *packetConn) Read(b []byte) (int, error) {
+	n, err := c.pending.Read(b)
+	if err == io.EOF && n > 0 {
+		return n, nil
+	}
+	return n, err
+}
+
+func (c *packetConn) Write(b []byte) (int, error) {
+	return c.conn.WriteTo(b, c.remote)
+}
+
+// Close is a no-op: packetConn shares the listener's underlying socket
+// with every other in-flight request, so it must not be closed here.
+func (c *packetConn) Close() error { return nil }
+
+func (c *packetConn) LocalAddr() net.Addr  { return c.conn.LocalAddr() }
+func (c *packetConn) RemoteAddr() net.Addr { return c.remote }
+
+func (c *packetConn) SetDeadline(t time.Time) error { return c.conn.SetDeadline(t) }
+
+func (c *packetConn) SetReadDeadline(t time.Time) error { return c.conn.SetReadDeadline(t) }
+
+func (c *packetConn) SetWriteDeadline(t time.Time) error { return c.conn.SetWriteDeadline(t) }
+
+var _ net.Conn = (*packetConn)(nil)
+var _ CredentialedConn = (*packetConn)(nil)
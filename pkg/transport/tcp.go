@@ -0,0 +1,37 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+)
+
+// TCP dials/listens on addr (host:port). If TLSConfig is non-nil,
+// connections are upgraded to TLS.
+type TCP struct {
+	Addr      string
+	TLSConfig *tls.Config
+}
+
+func (t TCP) Name() string {
+	if t.TLSConfig != nil {
+		return "tcp+tls"
+	}
+	return "tcp"
+}
+
+func (t TCP) Framing() Framing { return FramingStream }
+
+func (t TCP) Dial(ctx context.Context) (net.Conn, error) {
+	if t.TLSConfig != nil {
+		return (&tls.Dialer{Config: t.TLSConfig}).DialContext(ctx, "tcp", t.Addr)
+	}
+	return (&net.Dialer{}).DialContext(ctx, "tcp", t.Addr)
+}
+
+func (t TCP) Listen() (net.Listener, error) {
+	if t.TLSConfig != nil {
+		return tls.Listen("tcp", t.Addr, t.TLSConfig)
+	}
+	return net.Listen("tcp", t.Addr)
+}
@@ -0,0 +1,42 @@
+// Package transport abstracts the network Janus clients and servers run
+// over, so the wire-level JSON envelope (native or JSON-RPC 2.0, see
+// pkg/protocol) stays the same whether it travels over a Unix domain
+// socket, TCP/TLS, or vsock.
+package transport
+
+import (
+	"context"
+	"net"
+)
+
+// Framing describes how a Transport delimits individual messages.
+// Connection-oriented transports need an explicit length prefix; packet
+// transports get message boundaries for free from the underlying
+// datagram, so no prefix is written.
+type Framing int
+
+const (
+	// FramingStream is used by byte-stream transports (Unix stream
+	// sockets, TCP): each message is a 4-byte big-endian length prefix
+	// followed by that many bytes of JSON.
+	FramingStream Framing = iota
+	// FramingPacket is used by datagram transports (Unix datagrams):
+	// each message is exactly one datagram of JSON, no length prefix.
+	FramingPacket
+)
+
+// Transport is how a Client dials a server and a JanusServer listens for
+// connections. Implementations exist for Unix domain sockets (stream and
+// datagram), TCP (optionally with TLS), and vsock.
+type Transport interface {
+	// Name identifies the transport, advertised in the server manifest
+	// (see pkg/server's built-in "manifest" request) so a discovery
+	// client can pick one the server actually supports.
+	Name() string
+	// Framing reports how this transport delimits messages on the wire.
+	Framing() Framing
+	// Dial opens a connection to the server described by this transport.
+	Dial(ctx context.Context) (net.Conn, error)
+	// Listen binds the server side of this transport.
+	Listen() (net.Listener, error)
+}
@@ -0,0 +1,25 @@
+package transport
+
+import (
+	"context"
+	"net"
+	"os"
+)
+
+// Unix dials/listens on a Unix domain stream socket — the historical
+// Janus default transport.
+type Unix struct {
+	SocketPath string
+}
+
+func (u Unix) Name() string     { return "unix" }
+func (u Unix) Framing() Framing { return FramingStream }
+
+func (u Unix) Dial(ctx context.Context) (net.Conn, error) {
+	return (&net.Dialer{}).DialContext(ctx, "unix", u.SocketPath)
+}
+
+func (u Unix) Listen() (net.Listener, error) {
+	os.Remove(u.SocketPath)
+	return net.Listen("unix", u.SocketPath)
+}
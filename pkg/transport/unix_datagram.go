@@ -0,0 +1,68 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+)
+
+// UnixDatagram dials/listens on a Unix domain datagram (SOCK_DGRAM)
+// socket. Each request/response is a single datagram rather than a
+// framed byte stream.
+type UnixDatagram struct {
+	SocketPath string
+}
+
+func (g UnixDatagram) Name() string     { return "unixgram" }
+func (g UnixDatagram) Framing() Framing { return FramingPacket }
+
+var clientSocketSeq atomic.Uint64
+
+// Dial binds a throwaway local socket path so the server has an address
+// to send its reply datagram to; unixgram sockets have no OS-assigned
+// ephemeral local address the way UDP sockets do.
+func (g UnixDatagram) Dial(ctx context.Context) (net.Conn, error) {
+	n := clientSocketSeq.Add(1)
+	localPath := fmt.Sprintf("%s.client-%d-%d", g.SocketPath, os.Getpid(), n)
+	os.Remove(localPath)
+
+	conn, err := net.DialUnix("unixgram",
+		&net.UnixAddr{Name: localPath, Net: "unixgram"},
+		&net.UnixAddr{Name: g.SocketPath, Net: "unixgram"})
+	if err != nil {
+		os.Remove(localPath)
+		return nil, err
+	}
+	return &unixDatagramClientConn{UnixConn: conn, localPath: localPath}, nil
+}
+
+// unixDatagramClientConn removes the throwaway local socket file on Close.
+type unixDatagramClientConn struct {
+	*net.UnixConn
+	localPath string
+}
+
+func (c *unixDatagramClientConn) Close() error {
+	err := c.UnixConn.Close()
+	os.Remove(c.localPath)
+	return err
+}
+
+func (g UnixDatagram) Listen() (net.Listener, error) {
+	os.Remove(g.SocketPath)
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: g.SocketPath, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	// Enables per-datagram peer credentials on platforms that support it
+	// (see packet_creds_linux.go); a no-op elsewhere, so CredentialedConn's
+	// ok return is still the authoritative way to tell whether credentials
+	// are actually available.
+	if err := enablePassCred(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("transport: enable peer credentials: %w", err)
+	}
+	return newPacketListener(conn), nil
+}
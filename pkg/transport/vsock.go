@@ -0,0 +1,76 @@
+//go:build linux
+
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// VSock dials/listens over AF_VSOCK, the hypervisor<->guest socket
+// address family, for Janus servers running inside a VM to be reachable
+// from (or to reach) the host without a network interface.
+type VSock struct {
+	CID  uint32 // unix.VMADDR_CID_ANY to listen on all CIDs
+	Port uint32
+}
+
+func (v VSock) Name() string     { return "vsock" }
+func (v VSock) Framing() Framing { return FramingStream }
+
+func (v VSock) Dial(ctx context.Context) (net.Conn, error) {
+	fd, err := unix.Socket(unix.AF_VSOCK, unix.SOCK_STREAM, 0)
+	if err != nil {
+		return nil, fmt.Errorf("transport: vsock socket: %w", err)
+	}
+	f := os.NewFile(uintptr(fd), "vsock")
+
+	addr := &unix.SockaddrVM{CID: v.CID, Port: v.Port}
+	errCh := make(chan error, 1)
+	go func() { errCh <- unix.Connect(fd, addr) }()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("transport: vsock connect: %w", err)
+		}
+	case <-ctx.Done():
+		f.Close()
+		return nil, ctx.Err()
+	}
+
+	conn, err := net.FileConn(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("transport: vsock file conn: %w", err)
+	}
+	return conn, nil
+}
+
+func (v VSock) Listen() (net.Listener, error) {
+	fd, err := unix.Socket(unix.AF_VSOCK, unix.SOCK_STREAM, 0)
+	if err != nil {
+		return nil, fmt.Errorf("transport: vsock socket: %w", err)
+	}
+	if err := unix.Bind(fd, &unix.SockaddrVM{CID: v.CID, Port: v.Port}); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("transport: vsock bind: %w", err)
+	}
+	if err := unix.Listen(fd, unix.SOMAXCONN); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("transport: vsock listen: %w", err)
+	}
+
+	f := os.NewFile(uintptr(fd), "vsock")
+	l, err := net.FileListener(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("transport: vsock file listener: %w", err)
+	}
+	return l, nil
+}